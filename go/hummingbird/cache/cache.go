@@ -0,0 +1,116 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package cache provides a small metadata caching subsystem used by the
+// proxy server to avoid round-tripping every account request to the
+// backend client.
+package cache
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AccountInfo is the cached representation of a HeadAccount response.
+type AccountInfo struct {
+	Headers http.Header
+	Code    int
+}
+
+// MetadataCache is the interface the proxy account handlers consult before
+// calling through to the backend, and write through to on mutation.
+type MetadataCache interface {
+	GetAccount(account string) (*AccountInfo, bool)
+	SetAccount(account string, info *AccountInfo, ttl time.Duration)
+	InvalidateAccount(account string)
+
+	// Hits and Misses report cumulative counters for metrics reporting.
+	Hits() int64
+	Misses() int64
+}
+
+// lruEntry is a single slot in localLRU's eviction list.
+type lruEntry struct {
+	account string
+	info    *AccountInfo
+	expires time.Time
+}
+
+// localLRU is a small in-process LRU that sits in front of a backing cache
+// (typically Redis) to absorb bursts of requests against the same hot
+// account without a network round trip for every one of them.
+type localLRU struct {
+	lock     sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func newLocalLRU(capacity int) *localLRU {
+	return &localLRU{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *localLRU) get(account string) (*AccountInfo, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	elem, ok := c.items[account]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		c.ll.Remove(elem)
+		delete(c.items, account)
+		return nil, false
+	}
+	c.ll.MoveToFront(elem)
+	return entry.info, true
+}
+
+func (c *localLRU) set(account string, info *AccountInfo, ttl time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if elem, ok := c.items[account]; ok {
+		c.ll.MoveToFront(elem)
+		elem.Value.(*lruEntry).info = info
+		elem.Value.(*lruEntry).expires = time.Now().Add(ttl)
+		return
+	}
+	elem := c.ll.PushFront(&lruEntry{account: account, info: info, expires: time.Now().Add(ttl)})
+	c.items[account] = elem
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).account)
+	}
+}
+
+func (c *localLRU) remove(account string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if elem, ok := c.items[account]; ok {
+		c.ll.Remove(elem)
+		delete(c.items, account)
+	}
+}
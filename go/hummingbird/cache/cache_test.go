@@ -0,0 +1,71 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocalLRUGetMiss(t *testing.T) {
+	l := newLocalLRU(4)
+	if _, ok := l.get("a"); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+}
+
+func TestLocalLRUSetGet(t *testing.T) {
+	l := newLocalLRU(4)
+	info := &AccountInfo{Code: 204}
+	l.set("a", info, time.Minute)
+	got, ok := l.get("a")
+	if !ok || got.Code != 204 {
+		t.Fatalf("expected hit with Code 204, got %+v, %v", got, ok)
+	}
+}
+
+func TestLocalLRUExpires(t *testing.T) {
+	l := newLocalLRU(4)
+	l.set("a", &AccountInfo{Code: 204}, -time.Second)
+	if _, ok := l.get("a"); ok {
+		t.Fatal("expected entry to have already expired")
+	}
+}
+
+func TestLocalLRUEvictsOldest(t *testing.T) {
+	l := newLocalLRU(2)
+	l.set("a", &AccountInfo{Code: 200}, time.Minute)
+	l.set("b", &AccountInfo{Code: 200}, time.Minute)
+	l.set("c", &AccountInfo{Code: 200}, time.Minute)
+	if _, ok := l.get("a"); ok {
+		t.Fatal("expected oldest entry to be evicted once capacity was exceeded")
+	}
+	if _, ok := l.get("b"); !ok {
+		t.Fatal("expected b to still be cached")
+	}
+	if _, ok := l.get("c"); !ok {
+		t.Fatal("expected c to still be cached")
+	}
+}
+
+func TestLocalLRURemove(t *testing.T) {
+	l := newLocalLRU(4)
+	l.set("a", &AccountInfo{Code: 200}, time.Minute)
+	l.remove("a")
+	if _, ok := l.get("a"); ok {
+		t.Fatal("expected entry to be gone after remove")
+	}
+}
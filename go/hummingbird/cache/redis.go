@@ -0,0 +1,141 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/redis.v3"
+)
+
+// RedisConfig holds the settings read from the proxy-server config file's
+// "[filter:cache]" section.
+type RedisConfig struct {
+	Addr         string
+	Password     string
+	DB           int64
+	IdleTimeout  time.Duration
+	AccountTTL   time.Duration
+	LocalLRUSize int
+}
+
+// RedisCache is a MetadataCache backed by Redis, with a small local LRU in
+// front of it to absorb hot-key bursts without a network round trip.
+type RedisCache struct {
+	client     *redis.Client
+	accountTTL time.Duration
+	local      *localLRU
+
+	hits   int64
+	misses int64
+}
+
+// NewRedisCache builds a Redis client from the given config and returns a
+// ready to use MetadataCache; connections are dialed lazily, on first use,
+// not here. The connection pool's idle connections are recycled
+// after cfg.IdleTimeout so long-lived proxies don't accumulate dead
+// connections.
+func NewRedisCache(cfg RedisConfig) *RedisCache {
+	client := redis.NewClient(&redis.Options{
+		Addr:        cfg.Addr,
+		Password:    cfg.Password,
+		DB:          cfg.DB,
+		IdleTimeout: cfg.IdleTimeout,
+	})
+	localLRUSize := cfg.LocalLRUSize
+	if localLRUSize <= 0 {
+		localLRUSize = 1024
+	}
+	return &RedisCache{
+		client:     client,
+		accountTTL: cfg.AccountTTL,
+		local:      newLocalLRU(localLRUSize),
+	}
+}
+
+func accountCacheKey(account string) string {
+	return "account/" + account
+}
+
+func encodeAccountInfo(info *AccountInfo) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(info); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeAccountInfo(data []byte) (*AccountInfo, error) {
+	info := &AccountInfo{Headers: http.Header{}}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetAccount returns the cached HeadAccount response for account, first
+// checking the local LRU and falling back to Redis.
+func (r *RedisCache) GetAccount(account string) (*AccountInfo, bool) {
+	if info, ok := r.local.get(account); ok {
+		atomic.AddInt64(&r.hits, 1)
+		return info, true
+	}
+	data, err := r.client.Get(accountCacheKey(account)).Bytes()
+	if err != nil {
+		atomic.AddInt64(&r.misses, 1)
+		return nil, false
+	}
+	info, err := decodeAccountInfo(data)
+	if err != nil {
+		atomic.AddInt64(&r.misses, 1)
+		return nil, false
+	}
+	r.local.set(account, info, r.accountTTL)
+	atomic.AddInt64(&r.hits, 1)
+	return info, true
+}
+
+// SetAccount writes info through to Redis and the local LRU. ttl of zero
+// falls back to the cache's configured AccountTTL.
+func (r *RedisCache) SetAccount(account string, info *AccountInfo, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = r.accountTTL
+	}
+	r.local.set(account, info, ttl)
+	data, err := encodeAccountInfo(info)
+	if err != nil {
+		return
+	}
+	r.client.Set(accountCacheKey(account), data, ttl)
+}
+
+// InvalidateAccount drops any cached entry for account, local and remote.
+// Handlers call this on PUT and DELETE so a subsequent GET or HEAD doesn't
+// serve stale metadata.
+func (r *RedisCache) InvalidateAccount(account string) {
+	r.local.remove(account)
+	r.client.Del(accountCacheKey(account))
+}
+
+// Hits returns the cumulative number of cache hits (local or Redis).
+func (r *RedisCache) Hits() int64 { return atomic.LoadInt64(&r.hits) }
+
+// Misses returns the cumulative number of cache misses.
+func (r *RedisCache) Misses() int64 { return atomic.LoadInt64(&r.misses) }
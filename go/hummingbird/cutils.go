@@ -20,27 +20,34 @@ import (
 	"unsafe"
 )
 
+// FGetXattr reads a single attr off fd into value. It's kept for
+// callers that only need one attribute; internally it goes through the
+// same cached, pooled-buffer path as FGetXattrs, which surfaces any read
+// failure other than "attribute not set" as a real error rather than a
+// missing result key.
 func FGetXattr(fd uintptr, attr string, value []byte) (int, error) {
-	attrp, err := syscall.BytePtrFromString(attr)
+	result, err := FGetXattrs(fd, []string{attr})
 	if err != nil {
 		return 0, err
 	}
+	data, ok := result[attr]
+	if !ok {
+		return 0, syscall.ENODATA
+	}
 	if len(value) == 0 {
-		if r0, _, e1 := syscall.Syscall6(syscall.SYS_FGETXATTR, fd, uintptr(unsafe.Pointer(attrp)), 0, 0, 0, 0); e1 == 0 {
-			return int(r0), nil
-		} else {
-			return 0, e1
-		}
-	} else {
-		valuep := unsafe.Pointer(&value[0])
-		if r0, _, e1 := syscall.Syscall6(syscall.SYS_FGETXATTR, fd, uintptr(unsafe.Pointer(attrp)), uintptr(valuep), uintptr(len(value)), 0, 0); e1 == 0 {
-			return int(r0), nil
-		} else {
-			return int(r0), e1
-		}
+		return len(data), nil
+	}
+	if len(data) > len(value) {
+		return 0, syscall.ERANGE
 	}
+	return copy(value, data), nil
 }
 
+// FSetXattr sets a single attr on fd. It invalidates fd's entry in the
+// FGetXattrs cache so a subsequent read doesn't serve the pre-write map:
+// setting an xattr in place doesn't bump the file's mtime on Linux, and the
+// cache key's ctime component isn't guaranteed to change at the same
+// resolution this runs at either.
 func FSetXattr(fd uintptr, attr string, value []byte) (int, error) {
 	attrp, err := syscall.BytePtrFromString(attr)
 	if err != nil {
@@ -49,7 +56,8 @@ func FSetXattr(fd uintptr, attr string, value []byte) (int, error) {
 	valuep := unsafe.Pointer(&value[0])
 	r0, _, e1 := syscall.Syscall6(syscall.SYS_FSETXATTR, fd, uintptr(unsafe.Pointer(attrp)), uintptr(valuep), uintptr(len(value)), 0, 0)
 	if e1 != 0 {
-		err = e1
+		return int(r0), e1
 	}
+	invalidateXattrCache(fd)
 	return int(r0), nil
 }
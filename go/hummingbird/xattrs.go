@@ -0,0 +1,244 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package hummingbird
+
+import (
+	"bytes"
+	"container/list"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// xattrBufSize is the starting size handed out by xattrBufPool. It's sized
+// to comfortably hold Swift's chunked "user.swift.metadata*" attributes
+// without a reallocation in the common case.
+const xattrBufSize = 4096
+
+// xattrBufPool reuses read buffers across FGetXattr/FGetXattrs calls so the
+// replicator and auditor sweeps, which read many small xattrs off the same
+// fds, don't allocate on every read.
+var xattrBufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, xattrBufSize)
+		return &b
+	},
+}
+
+// xattrCacheKey identifies a file's xattr set well enough to cache it: the
+// device and inode it lives on, plus its ctime. ctime (not mtime) changes on
+// an in-place xattr update, but FSetXattr also explicitly invalidates the
+// entry below so the key's time component is only a second line of defense.
+type xattrCacheKey struct {
+	dev   uint64
+	ino   uint64
+	ctime int64
+}
+
+type xattrCacheEntry struct {
+	key   xattrCacheKey
+	attrs map[string][]byte
+}
+
+// xattrCache is a small per-fd LRU in front of the listxattr/getxattr
+// syscalls, keyed by (device, inode, ctime) so a file whose ctime has moved
+// on since it was cached misses rather than serving the old listing.
+type xattrCache struct {
+	lock     sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[xattrCacheKey]*list.Element
+}
+
+func newXattrCache(capacity int) *xattrCache {
+	return &xattrCache{capacity: capacity, ll: list.New(), items: make(map[xattrCacheKey]*list.Element)}
+}
+
+func (c *xattrCache) get(key xattrCacheKey) (map[string][]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(elem)
+	return elem.Value.(*xattrCacheEntry).attrs, true
+}
+
+func (c *xattrCache) set(key xattrCacheKey, attrs map[string][]byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if elem, ok := c.items[key]; ok {
+		c.ll.MoveToFront(elem)
+		elem.Value.(*xattrCacheEntry).attrs = attrs
+		return
+	}
+	elem := c.ll.PushFront(&xattrCacheEntry{key: key, attrs: attrs})
+	c.items[key] = elem
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*xattrCacheEntry).key)
+	}
+}
+
+// invalidateDevIno drops every cached entry for the given (dev, ino),
+// regardless of the ctime they were cached under. FSetXattr calls this
+// directly, since it can't know in advance what the file's new ctime will
+// be and relying on the key alone to roll over would leave a stale entry
+// reachable until LRU eviction.
+func (c *xattrCache) invalidateDevIno(dev, ino uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for key, elem := range c.items {
+		if key.dev == dev && key.ino == ino {
+			c.ll.Remove(elem)
+			delete(c.items, key)
+		}
+	}
+}
+
+// globalXattrCache is shared by all FGetXattrs callers; replicator and
+// auditor sweeps run many goroutines over the same small set of device
+// fds, so a package-level cache is more useful than one scoped per caller.
+var globalXattrCache = newXattrCache(4096)
+
+// fxattrCacheKey stats fd to build its cache key. Callers already hold the
+// fd open, so this is just the one fstat syscall FGetXattrs needs anyway.
+func fxattrCacheKey(fd uintptr) (xattrCacheKey, error) {
+	var stat syscall.Stat_t
+	if err := syscall.Fstat(int(fd), &stat); err != nil {
+		return xattrCacheKey{}, err
+	}
+	return xattrCacheKey{dev: uint64(stat.Dev), ino: uint64(stat.Ino), ctime: stat.Ctim.Sec}, nil
+}
+
+// invalidateXattrCache drops any cached xattr listing for fd's underlying
+// file. FSetXattr calls this after a successful write so the next
+// FGetXattr/FGetXattrs on the same fd doesn't serve the pre-write map.
+func invalidateXattrCache(fd uintptr) {
+	var stat syscall.Stat_t
+	if err := syscall.Fstat(int(fd), &stat); err != nil {
+		return
+	}
+	globalXattrCache.invalidateDevIno(uint64(stat.Dev), uint64(stat.Ino))
+}
+
+// flistxattr lists the xattr names set on fd, growing buf from the pool
+// until the listing fits.
+func flistxattr(fd uintptr) ([]string, error) {
+	bufp := xattrBufPool.Get().(*[]byte)
+	defer xattrBufPool.Put(bufp)
+	buf := *bufp
+	for {
+		r0, _, errno := syscall.Syscall(syscall.SYS_FLISTXATTR, fd, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+		if errno == syscall.ERANGE {
+			buf = make([]byte, len(buf)*2)
+			continue
+		}
+		if errno != 0 {
+			return nil, errno
+		}
+		names := make([]string, 0, 8)
+		for _, chunk := range bytes.Split(buf[:r0], []byte{0}) {
+			if len(chunk) > 0 {
+				names = append(names, string(chunk))
+			}
+		}
+		return names, nil
+	}
+}
+
+// fgetxattrRaw reads a single attr off fd, growing buf from the pool until
+// the value fits. This is the single-syscall primitive both FGetXattr and
+// FGetXattrs bottom out on.
+func fgetxattrRaw(fd uintptr, attr string) ([]byte, error) {
+	attrp, err := syscall.BytePtrFromString(attr)
+	if err != nil {
+		return nil, err
+	}
+	bufp := xattrBufPool.Get().(*[]byte)
+	defer xattrBufPool.Put(bufp)
+	buf := *bufp
+	for {
+		r0, _, errno := syscall.Syscall6(syscall.SYS_FGETXATTR, fd, uintptr(unsafe.Pointer(attrp)), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), 0, 0)
+		if errno == syscall.ERANGE {
+			buf = make([]byte, len(buf)*2)
+			continue
+		}
+		if errno != 0 {
+			return nil, errno
+		}
+		value := make([]byte, r0)
+		copy(value, buf[:r0])
+		return value, nil
+	}
+}
+
+// isMissingXattrErrno reports whether err is the expected, benign race
+// between listxattr and getxattr: a name listxattr returned can still
+// disappear (removed concurrently) before the matching getxattr runs. Any
+// other errno is a genuine read failure the caller needs to see, not
+// something to paper over as "attribute not set".
+func isMissingXattrErrno(err error) bool {
+	errno, ok := err.(syscall.Errno)
+	return ok && errno == syscall.ENODATA
+}
+
+// FGetXattrs reads attrs off fd in a single listxattr call followed by one
+// getxattr per attr that's actually set, instead of a blind getxattr per
+// requested name. The result is cached per-fd, keyed by (device, inode,
+// ctime), so repeated reads of the same unmodified file within a
+// replicator or auditor sweep cost only an fstat past the first. A getxattr
+// failure other than "attribute no longer there" aborts the read and
+// propagates the real errno, rather than silently reporting the attr as
+// unset - the replicator and auditor rely on that distinction to catch
+// disk corruption.
+func FGetXattrs(fd uintptr, attrs []string) (map[string][]byte, error) {
+	key, err := fxattrCacheKey(fd)
+	if err != nil {
+		return nil, err
+	}
+	cached, ok := globalXattrCache.get(key)
+	if !ok {
+		names, err := flistxattr(fd)
+		if err != nil {
+			return nil, err
+		}
+		cached = make(map[string][]byte, len(names))
+		for _, name := range names {
+			value, err := fgetxattrRaw(fd, name)
+			if err != nil {
+				if isMissingXattrErrno(err) {
+					continue
+				}
+				return nil, err
+			}
+			cached[name] = value
+		}
+		globalXattrCache.set(key, cached)
+	}
+	result := make(map[string][]byte, len(attrs))
+	for _, attr := range attrs {
+		if value, ok := cached[attr]; ok {
+			result[attr] = value
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package hummingbird
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestIsMissingXattrErrno(t *testing.T) {
+	if !isMissingXattrErrno(syscall.ENODATA) {
+		t.Fatal("expected ENODATA to be treated as a missing attribute")
+	}
+	if isMissingXattrErrno(syscall.EIO) {
+		t.Fatal("expected EIO to be treated as a real read failure, not a missing attribute")
+	}
+}
+
+func TestXattrCacheGetMiss(t *testing.T) {
+	c := newXattrCache(4)
+	if _, ok := c.get(xattrCacheKey{dev: 1, ino: 1, ctime: 1}); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+}
+
+func TestXattrCacheSetGet(t *testing.T) {
+	c := newXattrCache(4)
+	key := xattrCacheKey{dev: 1, ino: 2, ctime: 3}
+	attrs := map[string][]byte{"user.swift.metadata": []byte("v1")}
+	c.set(key, attrs)
+	got, ok := c.get(key)
+	if !ok || string(got["user.swift.metadata"]) != "v1" {
+		t.Fatalf("expected hit with v1, got %v, %v", got, ok)
+	}
+}
+
+// TestXattrCacheInvalidateDevIno exercises the path FSetXattr relies on:
+// dropping every entry for a (dev, ino) without knowing in advance what
+// ctime the next read will see.
+func TestXattrCacheInvalidateDevIno(t *testing.T) {
+	c := newXattrCache(4)
+	stale := xattrCacheKey{dev: 1, ino: 2, ctime: 3}
+	c.set(stale, map[string][]byte{"a": []byte("old")})
+	c.invalidateDevIno(1, 2)
+	if _, ok := c.get(stale); ok {
+		t.Fatal("expected entry to be gone after invalidateDevIno")
+	}
+}
+
+func TestXattrCacheInvalidateDevInoLeavesOtherInodes(t *testing.T) {
+	c := newXattrCache(4)
+	keyA := xattrCacheKey{dev: 1, ino: 2, ctime: 3}
+	keyB := xattrCacheKey{dev: 1, ino: 9, ctime: 3}
+	c.set(keyA, map[string][]byte{"a": []byte("a")})
+	c.set(keyB, map[string][]byte{"b": []byte("b")})
+	c.invalidateDevIno(1, 2)
+	if _, ok := c.get(keyB); !ok {
+		t.Fatal("expected unrelated inode's entry to survive invalidation")
+	}
+}
@@ -17,10 +17,17 @@ package proxyserver
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/openstack/swift/go/hummingbird"
+	"github.com/openstack/swift/go/hummingbird/cache"
 )
 
+// errorCacheTTL bounds how long a non-success response is allowed to sit in
+// the cache, so a transient 5xx or a 404 for a not-yet-created account isn't
+// replayed to every client for the full configured AccountTTL.
+const errorCacheTTL = 5 * time.Second
+
 func (server *ProxyServer) AccountGetHandler(writer http.ResponseWriter, request *http.Request) {
 	vars := hummingbird.GetVars(request)
 	ctx := GetProxyContext(request)
@@ -40,7 +47,11 @@ func (server *ProxyServer) AccountGetHandler(writer http.ResponseWriter, request
 		"prefix":     request.FormValue("prefix"),
 		"delimiter":  request.FormValue("delimiter"),
 	}
-	r, headers, code := server.C.GetAccount(vars["account"], options, request.Header)
+	account := vars["account"]
+	// The cache only ever holds the Head-shaped {Headers, Code} pair, not a
+	// listing body, so GET (which can return one) must always go to the
+	// backend; only AccountHeadHandler consults the cache.
+	r, headers, code := server.C.GetAccount(account, options, request.Header)
 	for k := range headers {
 		writer.Header().Set(k, headers.Get(k))
 	}
@@ -62,11 +73,28 @@ func (server *ProxyServer) AccountHeadHandler(writer http.ResponseWriter, reques
 		hummingbird.StandardResponse(writer, 401)
 		return
 	}
-	headers, code := server.C.HeadAccount(vars["account"], request.Header)
+	account := vars["account"]
+	if ctx.Cache != nil {
+		if info, ok := ctx.Cache.GetAccount(account); ok {
+			for k := range info.Headers {
+				writer.Header().Set(k, info.Headers.Get(k))
+			}
+			writer.WriteHeader(info.Code)
+			return
+		}
+	}
+	headers, code := server.C.HeadAccount(account, request.Header)
 	for k := range headers {
 		writer.Header().Set(k, headers.Get(k))
 	}
 	writer.WriteHeader(code)
+	if ctx.Cache != nil {
+		if code >= 200 && code < 300 {
+			ctx.Cache.SetAccount(account, &cache.AccountInfo{Headers: headers, Code: code}, 0)
+		} else {
+			ctx.Cache.SetAccount(account, &cache.AccountInfo{Headers: headers, Code: code}, errorCacheTTL)
+		}
+	}
 }
 
 func (server *ProxyServer) AccountPutHandler(writer http.ResponseWriter, request *http.Request) {
@@ -81,7 +109,11 @@ func (server *ProxyServer) AccountPutHandler(writer http.ResponseWriter, request
 		return
 	}
 	request.Header.Set("X-Timestamp", hummingbird.GetTimestamp())
-	hummingbird.StandardResponse(writer, server.C.PutAccount(vars["account"], request.Header))
+	code := server.C.PutAccount(vars["account"], request.Header)
+	if ctx.Cache != nil {
+		ctx.Cache.InvalidateAccount(vars["account"])
+	}
+	hummingbird.StandardResponse(writer, code)
 }
 
 func (server *ProxyServer) AccountDeleteHandler(writer http.ResponseWriter, request *http.Request) {
@@ -96,5 +128,9 @@ func (server *ProxyServer) AccountDeleteHandler(writer http.ResponseWriter, requ
 		return
 	}
 	request.Header.Set("X-Timestamp", hummingbird.GetTimestamp())
-	hummingbird.StandardResponse(writer, server.C.DeleteAccount(vars["account"], request.Header))
+	code := server.C.DeleteAccount(vars["account"], request.Header)
+	if ctx.Cache != nil {
+		ctx.Cache.InvalidateAccount(vars["account"])
+	}
+	hummingbird.StandardResponse(writer, code)
 }
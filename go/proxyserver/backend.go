@@ -0,0 +1,44 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package proxyserver
+
+import (
+	"io"
+	"net/http"
+)
+
+// ProxyBackend is what the account, container, and object handlers talk to
+// instead of calling Swift's ring-based client directly. server.C must
+// satisfy this interface; the default implementation dispatches to the
+// account/container/object rings the way it always has, and
+// backend.B2Backend is an alternate implementation that targets a
+// Backblaze B2 bucket instead.
+type ProxyBackend interface {
+	GetAccount(account string, options map[string]string, headers http.Header) (io.ReadCloser, http.Header, int)
+	HeadAccount(account string, headers http.Header) (http.Header, int)
+	PutAccount(account string, headers http.Header) int
+	DeleteAccount(account string, headers http.Header) int
+
+	GetContainer(account, container string, options map[string]string, headers http.Header) (io.ReadCloser, http.Header, int)
+	HeadContainer(account, container string, headers http.Header) (http.Header, int)
+	PutContainer(account, container string, headers http.Header) int
+	DeleteContainer(account, container string, headers http.Header) int
+
+	GetObject(account, container, object string, headers http.Header) (io.ReadCloser, http.Header, int)
+	HeadObject(account, container, object string, headers http.Header) (http.Header, int)
+	PutObject(account, container, object string, headers http.Header, body io.Reader) int
+	DeleteObject(account, container, object string, headers http.Header) int
+}
@@ -0,0 +1,353 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+// Package backend holds alternate ProxyBackend implementations. B2Backend
+// lets operators stand up a Swift-compatible frontend over a Backblaze B2
+// bucket instead of running account and container servers.
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kurin/blazer/b2"
+
+	"github.com/openstack/swift/go/proxyserver"
+)
+
+// Swift accounts map one-to-one onto B2 buckets; containers are a "/"
+// delimited prefix within that bucket, and objects are the remainder of
+// the file name past the container prefix.
+
+// B2Backend implements proxyserver.ProxyBackend against a Backblaze B2
+// account, so it can be swapped in for the ring-backed default without the
+// account/container/object handlers changing shape.
+type B2Backend struct {
+	client *b2.Client
+}
+
+// NewB2Backend authenticates against B2 with the given account ID and
+// application key and returns a ready to use ProxyBackend.
+func NewB2Backend(ctx context.Context, accountID, applicationKey string) (*B2Backend, error) {
+	client, err := b2.NewClient(ctx, accountID, applicationKey)
+	if err != nil {
+		return nil, err
+	}
+	return &B2Backend{client: client}, nil
+}
+
+// swiftContainerMarker is the zero-length object PutContainer uploads to
+// stand in for an otherwise-empty B2 prefix. It's filtered out of listings
+// and doesn't count toward a container's emptiness.
+const swiftContainerMarker = ".swift_container_marker"
+
+func containerKey(container string) string {
+	return container + "/"
+}
+
+func objectKey(container, object string) string {
+	return container + "/" + object
+}
+
+// splitObjectKey turns a B2 file name back into its Swift container and
+// object name, the inverse of objectKey.
+func splitObjectKey(key string) (container, object string) {
+	parts := strings.SplitN(key, "/", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// fileInfoHeaders maps a B2 file's metadata onto Swift's object headers.
+func fileInfoHeaders(info *b2.FileInfo) http.Header {
+	headers := http.Header{}
+	headers.Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	headers.Set("Content-Type", info.ContentType)
+	headers.Set("ETag", info.ContentSHA1)
+	for k, v := range info.Info {
+		headers.Set("X-Object-Meta-"+k, v)
+	}
+	return headers
+}
+
+// metaFromHeaders maps Swift's "X-Object-Meta-*" headers onto the B2 file
+// info map uploads are tagged with.
+func metaFromHeaders(headers http.Header) map[string]string {
+	meta := map[string]string{}
+	for k := range headers {
+		if strings.HasPrefix(strings.ToLower(k), "x-object-meta-") {
+			meta[k[len("X-Object-Meta-"):]] = headers.Get(k)
+		}
+	}
+	return meta
+}
+
+func (b *B2Backend) GetAccount(account string, options map[string]string, headers http.Header) (io.ReadCloser, http.Header, int) {
+	bucket, err := b.client.Bucket(context.Background(), account)
+	if err != nil {
+		return nil, nil, http.StatusNotFound
+	}
+	marker := options["marker"]
+	iter := bucket.List(context.Background(), b2.ListDelimiter("/"), b2.ListPrefix(""), b2.ListStart(marker))
+	type containerEntry struct {
+		Name string `json:"name"`
+	}
+	limit := -1
+	if l := options["limit"]; l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil {
+			limit = parsed
+		}
+	}
+	var entries []containerEntry
+	seen := map[string]bool{}
+	for iter.Next() {
+		container, _ := splitObjectKey(iter.Object().Name())
+		// B2's ListStart is inclusive but Swift's marker is exclusive, so
+		// the exact marker value (which the previous page already
+		// returned) is skipped rather than repeated at the head of this
+		// one.
+		if marker != "" && container == marker {
+			continue
+		}
+		if !seen[container] {
+			seen[container] = true
+			entries = append(entries, containerEntry{Name: container})
+			if limit >= 0 && len(entries) >= limit {
+				break
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, nil, http.StatusInternalServerError
+	}
+	body, err := json.Marshal(entries)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError
+	}
+	return io.NopCloser(strings.NewReader(string(body))), http.Header{"Content-Type": {"application/json"}}, http.StatusOK
+}
+
+func (b *B2Backend) HeadAccount(account string, headers http.Header) (http.Header, int) {
+	if _, err := b.client.Bucket(context.Background(), account); err != nil {
+		return nil, http.StatusNotFound
+	}
+	return http.Header{}, http.StatusNoContent
+}
+
+func (b *B2Backend) PutAccount(account string, headers http.Header) int {
+	if _, err := b.client.NewBucket(context.Background(), account, nil); err != nil {
+		return http.StatusInternalServerError
+	}
+	return http.StatusCreated
+}
+
+func (b *B2Backend) DeleteAccount(account string, headers http.Header) int {
+	bucket, err := b.client.Bucket(context.Background(), account)
+	if err != nil {
+		return http.StatusNotFound
+	}
+	if err := bucket.Delete(context.Background()); err != nil {
+		return http.StatusConflict
+	}
+	return http.StatusNoContent
+}
+
+// GetContainer lists the objects under container's prefix, translating
+// Swift's marker/end_marker/prefix/delimiter listing options to the
+// equivalent b2_list_file_names options.
+func (b *B2Backend) GetContainer(account, container string, options map[string]string, headers http.Header) (io.ReadCloser, http.Header, int) {
+	bucket, err := b.client.Bucket(context.Background(), account)
+	if err != nil {
+		return nil, nil, http.StatusNotFound
+	}
+	prefix := containerKey(container) + options["prefix"]
+	delimiter := options["delimiter"]
+	listOpts := []b2.ListOption{b2.ListPrefix(prefix)}
+	if delimiter != "" {
+		listOpts = append(listOpts, b2.ListDelimiter(delimiter))
+	}
+	marker := options["marker"]
+	if marker != "" {
+		listOpts = append(listOpts, b2.ListStart(objectKey(container, marker)))
+	}
+	iter := bucket.List(context.Background(), listOpts...)
+	type objectEntry struct {
+		Name   string `json:"name,omitempty"`
+		Bytes  int64  `json:"bytes,omitempty"`
+		Subdir string `json:"subdir,omitempty"`
+	}
+	limit := -1
+	if l := options["limit"]; l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil {
+			limit = parsed
+		}
+	}
+	var entries []objectEntry
+	for iter.Next() {
+		obj := iter.Object()
+		_, name := splitObjectKey(obj.Name())
+		if name == swiftContainerMarker {
+			continue
+		}
+		// B2's ListStart is inclusive but Swift's marker is exclusive, so
+		// the exact marker value is skipped rather than repeated at the
+		// head of this page.
+		if marker != "" && name == marker {
+			continue
+		}
+		if options["end_marker"] != "" && name >= options["end_marker"] {
+			break
+		}
+		if delimiter != "" && strings.HasSuffix(name, delimiter) {
+			// B2 represents a delimiter listing's common-prefix "folder"
+			// entries this way; there's no underlying file to Attrs, so
+			// it's translated straight to Swift's {"subdir": "..."}
+			// pseudo-entry instead of being dropped.
+			entries = append(entries, objectEntry{Subdir: name})
+			if limit >= 0 && len(entries) >= limit {
+				break
+			}
+			continue
+		}
+		info, err := obj.Attrs(context.Background())
+		if err != nil {
+			continue
+		}
+		entries = append(entries, objectEntry{Name: name, Bytes: info.Size})
+		if limit >= 0 && len(entries) >= limit {
+			break
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, nil, http.StatusInternalServerError
+	}
+	body, err := json.Marshal(entries)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError
+	}
+	return io.NopCloser(strings.NewReader(string(body))), http.Header{"Content-Type": {"application/json"}}, http.StatusOK
+}
+
+func (b *B2Backend) HeadContainer(account, container string, headers http.Header) (http.Header, int) {
+	bucket, err := b.client.Bucket(context.Background(), account)
+	if err != nil {
+		return nil, http.StatusNotFound
+	}
+	iter := bucket.List(context.Background(), b2.ListPrefix(containerKey(container)))
+	if !iter.Next() {
+		return nil, http.StatusNotFound
+	}
+	return http.Header{}, http.StatusNoContent
+}
+
+// PutContainer has no B2 equivalent to creating an empty prefix, so it
+// uploads a zero-length marker object at the container's prefix.
+func (b *B2Backend) PutContainer(account, container string, headers http.Header) int {
+	bucket, err := b.client.Bucket(context.Background(), account)
+	if err != nil {
+		return http.StatusNotFound
+	}
+	writer := bucket.Object(containerKey(container) + swiftContainerMarker).NewWriter(context.Background())
+	if err := writer.Close(); err != nil {
+		return http.StatusInternalServerError
+	}
+	return http.StatusCreated
+}
+
+// DeleteContainer refuses to delete a container that still holds objects,
+// matching Swift's "409 Conflict unless empty" contract; only the
+// zero-length marker PutContainer created is cleaned up.
+func (b *B2Backend) DeleteContainer(account, container string, headers http.Header) int {
+	bucket, err := b.client.Bucket(context.Background(), account)
+	if err != nil {
+		return http.StatusNotFound
+	}
+	prefix := containerKey(container)
+	markerKey := prefix + swiftContainerMarker
+	iter := bucket.List(context.Background(), b2.ListPrefix(prefix))
+	for iter.Next() {
+		if iter.Object().Name() != markerKey {
+			return http.StatusConflict
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return http.StatusInternalServerError
+	}
+	if err := bucket.Object(markerKey).Delete(context.Background()); err != nil && err != b2.ErrNotExist {
+		return http.StatusInternalServerError
+	}
+	return http.StatusNoContent
+}
+
+func (b *B2Backend) GetObject(account, container, object string, headers http.Header) (io.ReadCloser, http.Header, int) {
+	bucket, err := b.client.Bucket(context.Background(), account)
+	if err != nil {
+		return nil, nil, http.StatusNotFound
+	}
+	obj := bucket.Object(objectKey(container, object))
+	info, err := obj.Attrs(context.Background())
+	if err != nil {
+		return nil, nil, http.StatusNotFound
+	}
+	return obj.NewReader(context.Background()), fileInfoHeaders(info), http.StatusOK
+}
+
+func (b *B2Backend) HeadObject(account, container, object string, headers http.Header) (http.Header, int) {
+	bucket, err := b.client.Bucket(context.Background(), account)
+	if err != nil {
+		return nil, http.StatusNotFound
+	}
+	info, err := bucket.Object(objectKey(container, object)).Attrs(context.Background())
+	if err != nil {
+		return nil, http.StatusNotFound
+	}
+	return fileInfoHeaders(info), http.StatusOK
+}
+
+func (b *B2Backend) PutObject(account, container, object string, headers http.Header, body io.Reader) int {
+	bucket, err := b.client.Bucket(context.Background(), account)
+	if err != nil {
+		return http.StatusNotFound
+	}
+	writer := bucket.Object(objectKey(container, object)).NewWriter(context.Background())
+	writer.ContentType = headers.Get("Content-Type")
+	writer.Info = metaFromHeaders(headers)
+	if _, err := io.Copy(writer, body); err != nil {
+		writer.Close()
+		return http.StatusInternalServerError
+	}
+	if err := writer.Close(); err != nil {
+		return http.StatusInternalServerError
+	}
+	return http.StatusCreated
+}
+
+func (b *B2Backend) DeleteObject(account, container, object string, headers http.Header) int {
+	bucket, err := b.client.Bucket(context.Background(), account)
+	if err != nil {
+		return http.StatusNotFound
+	}
+	if err := bucket.Object(objectKey(container, object)).Delete(context.Background()); err != nil {
+		return http.StatusNotFound
+	}
+	return http.StatusNoContent
+}
+
+var _ proxyserver.ProxyBackend = (*B2Backend)(nil)
@@ -0,0 +1,39 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package backend
+
+import "testing"
+
+func TestObjectKeyRoundTrip(t *testing.T) {
+	key := objectKey("mycontainer", "a/b/c.txt")
+	container, object := splitObjectKey(key)
+	if container != "mycontainer" || object != "a/b/c.txt" {
+		t.Fatalf("expected mycontainer/a/b/c.txt, got %s/%s", container, object)
+	}
+}
+
+func TestSplitObjectKeyNoObject(t *testing.T) {
+	container, object := splitObjectKey("mycontainer")
+	if container != "mycontainer" || object != "" {
+		t.Fatalf("expected mycontainer with no object, got %s/%s", container, object)
+	}
+}
+
+func TestContainerKeyIsObjectKeyPrefix(t *testing.T) {
+	if containerKey("mycontainer") != objectKey("mycontainer", "") {
+		t.Fatalf("expected containerKey to equal objectKey with an empty object name")
+	}
+}
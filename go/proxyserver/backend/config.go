@@ -0,0 +1,41 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package backend
+
+import (
+	"context"
+
+	"github.com/openstack/swift/go/proxyserver"
+)
+
+// NewBackendFromConfig builds a B2Backend from the proxy-server config
+// file's "[app:proxy-server]" section when both b2_account_id and
+// b2_application_key are set, so operators can select the B2 driver
+// without editing code. It returns a nil ProxyBackend and a nil error when
+// B2 isn't configured, leaving the caller's default ring-backed client in
+// place.
+func NewBackendFromConfig(ctx context.Context, section map[string]string) (proxyserver.ProxyBackend, error) {
+	accountID := section["b2_account_id"]
+	applicationKey := section["b2_application_key"]
+	if accountID == "" || applicationKey == "" {
+		return nil, nil
+	}
+	backend, err := NewB2Backend(ctx, accountID, applicationKey)
+	if err != nil {
+		return nil, err
+	}
+	return backend, nil
+}
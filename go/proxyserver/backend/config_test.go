@@ -0,0 +1,41 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewBackendFromConfigWithoutB2Keys(t *testing.T) {
+	backend, err := NewBackendFromConfig(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if backend != nil {
+		t.Fatalf("expected no backend when B2 isn't configured, got %+v", backend)
+	}
+}
+
+func TestNewBackendFromConfigWithOnlyAccountID(t *testing.T) {
+	backend, err := NewBackendFromConfig(context.Background(), map[string]string{"b2_account_id": "id"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if backend != nil {
+		t.Fatalf("expected no backend without b2_application_key, got %+v", backend)
+	}
+}
@@ -0,0 +1,62 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package proxyserver
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/openstack/swift/go/hummingbird/cache"
+)
+
+// NewRedisConfigFromSection builds a cache.RedisConfig from the
+// proxy-server config file's "[filter:cache]" section (the one redis.go's
+// doc comment references). Keys that are missing or fail to parse fall
+// back to their zero value; NewRedisCache fills in a sane default for
+// LocalLRUSize when it's left at zero.
+func NewRedisConfigFromSection(section map[string]string) cache.RedisConfig {
+	cfg := cache.RedisConfig{
+		Addr:     section["addr"],
+		Password: section["password"],
+	}
+	if db, err := strconv.ParseInt(section["db"], 10, 64); err == nil {
+		cfg.DB = db
+	}
+	if idle, err := time.ParseDuration(section["idle_timeout"]); err == nil {
+		cfg.IdleTimeout = idle
+	}
+	if ttl, err := time.ParseDuration(section["account_ttl"]); err == nil {
+		cfg.AccountTTL = ttl
+	}
+	if size, err := strconv.Atoi(section["local_lru_size"]); err == nil {
+		cfg.LocalLRUSize = size
+	}
+	return cfg
+}
+
+// NewProxyServer wires up a ProxyServer backed by backend, authorizing
+// requests with authorize. cacheSection is the proxy-server config file's
+// "[filter:cache]" section; when it's absent or has no "addr" set, Cache
+// is left nil and the account handlers always fall through to backend,
+// same as before this cache subsystem existed.
+func NewProxyServer(backend ProxyBackend, authorize func(*http.Request) bool, cacheSection map[string]string) *ProxyServer {
+	server := &ProxyServer{C: backend, Authorize: authorize}
+	if cacheSection["addr"] != "" {
+		server.Cache = cache.NewRedisCache(NewRedisConfigFromSection(cacheSection))
+	}
+	return server
+}
@@ -0,0 +1,60 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package proxyserver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRedisConfigFromSection(t *testing.T) {
+	section := map[string]string{
+		"addr":           "localhost:6379",
+		"password":       "secret",
+		"db":             "2",
+		"idle_timeout":   "30s",
+		"account_ttl":    "1m",
+		"local_lru_size": "2048",
+	}
+	cfg := NewRedisConfigFromSection(section)
+	if cfg.Addr != "localhost:6379" || cfg.Password != "secret" || cfg.DB != 2 {
+		t.Fatalf("unexpected connection settings: %+v", cfg)
+	}
+	if cfg.IdleTimeout != 30*time.Second || cfg.AccountTTL != time.Minute || cfg.LocalLRUSize != 2048 {
+		t.Fatalf("unexpected tuning settings: %+v", cfg)
+	}
+}
+
+func TestNewRedisConfigFromSectionMissingKeysZeroValue(t *testing.T) {
+	cfg := NewRedisConfigFromSection(map[string]string{"addr": "localhost:6379"})
+	if cfg.DB != 0 || cfg.IdleTimeout != 0 || cfg.AccountTTL != 0 || cfg.LocalLRUSize != 0 {
+		t.Fatalf("expected zero values for unset keys, got %+v", cfg)
+	}
+}
+
+func TestNewProxyServerWithoutCacheSection(t *testing.T) {
+	server := NewProxyServer(nil, nil, nil)
+	if server.Cache != nil {
+		t.Fatal("expected no cache to be configured without a \"[filter:cache]\" section")
+	}
+}
+
+func TestNewProxyServerWithCacheSection(t *testing.T) {
+	server := NewProxyServer(nil, nil, map[string]string{"addr": "localhost:6379"})
+	if server.Cache == nil {
+		t.Fatal("expected a cache to be configured once addr is set")
+	}
+}
@@ -0,0 +1,89 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package proxyserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/openstack/swift/go/hummingbird/cache"
+)
+
+// StatsClient is the minimal interface ProxyServer needs to report account
+// cache hit/miss counts; a statsd client or a no-op stub both satisfy it.
+type StatsClient interface {
+	Gauge(stat string, value int64)
+}
+
+// ProxyServer holds everything the account, container, and object
+// handlers need across requests: the backend client, the shared account
+// metadata cache, the request authorization hook, and where hit/miss
+// metrics get reported. Both the HTTP handlers (through Middleware) and
+// AccountGRPCServer (through NewContext) build their per-request
+// ProxyContext from the same fields here, so the two surfaces can't drift.
+type ProxyServer struct {
+	C         ProxyBackend
+	Cache     cache.MetadataCache
+	Authorize func(*http.Request) bool
+	Stats     StatsClient
+}
+
+// ProxyContext carries the per-request state the account handlers
+// consult: the authorization hook and the account metadata cache.
+type ProxyContext struct {
+	Authorize func(*http.Request) bool
+	Cache     cache.MetadataCache
+}
+
+type proxyContextKey struct{}
+
+// NewContext builds the per-request ProxyContext for request from
+// server's shared Cache and Authorize hook. Middleware and
+// AccountGRPCServer both call this, so HTTP and gRPC stay in lockstep.
+func (server *ProxyServer) NewContext(request *http.Request) *ProxyContext {
+	return &ProxyContext{Authorize: server.Authorize, Cache: server.Cache}
+}
+
+// Middleware attaches a ProxyContext to every request before handing off
+// to next, so GetProxyContext has something to return in the account
+// handlers, and reports the account cache's hit/miss counters once the
+// request completes.
+func (server *ProxyServer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		ctx := server.NewContext(request)
+		request = request.WithContext(context.WithValue(request.Context(), proxyContextKey{}, ctx))
+		next.ServeHTTP(writer, request)
+		server.reportCacheMetrics(ctx)
+	})
+}
+
+// GetProxyContext returns the ProxyContext Middleware attached to
+// request, or nil if the request never went through it.
+func GetProxyContext(request *http.Request) *ProxyContext {
+	ctx, _ := request.Context().Value(proxyContextKey{}).(*ProxyContext)
+	return ctx
+}
+
+// reportCacheMetrics records the account cache's cumulative hit/miss
+// counters after a request, if both a cache and a stats client are
+// configured.
+func (server *ProxyServer) reportCacheMetrics(ctx *ProxyContext) {
+	if server.Stats == nil || ctx.Cache == nil {
+		return
+	}
+	server.Stats.Gauge("account_cache.hits", ctx.Cache.Hits())
+	server.Stats.Gauge("account_cache.misses", ctx.Cache.Misses())
+}
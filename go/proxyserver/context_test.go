@@ -0,0 +1,86 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package proxyserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openstack/swift/go/hummingbird/cache"
+)
+
+// fakeMetadataCache is a minimal cache.MetadataCache that always misses,
+// just enough to exercise Middleware's metrics reporting without a real
+// Redis connection.
+type fakeMetadataCache struct {
+	misses int64
+}
+
+func (f *fakeMetadataCache) GetAccount(account string) (*cache.AccountInfo, bool) {
+	f.misses++
+	return nil, false
+}
+func (f *fakeMetadataCache) SetAccount(account string, info *cache.AccountInfo, ttl time.Duration) {}
+func (f *fakeMetadataCache) InvalidateAccount(account string)                                      {}
+func (f *fakeMetadataCache) Hits() int64                                                           { return 0 }
+func (f *fakeMetadataCache) Misses() int64                                                         { return f.misses }
+
+func TestGetProxyContextWithoutMiddlewareIsNil(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/v1/a", nil)
+	if ctx := GetProxyContext(request); ctx != nil {
+		t.Fatalf("expected nil ProxyContext for a request that never went through Middleware, got %+v", ctx)
+	}
+}
+
+func TestMiddlewareAttachesProxyContext(t *testing.T) {
+	server := &ProxyServer{Authorize: func(*http.Request) bool { return true }}
+	var seen *ProxyContext
+	handler := server.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = GetProxyContext(r)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/a", nil))
+	if seen == nil {
+		t.Fatal("expected Middleware to attach a ProxyContext visible to the handler")
+	}
+	if seen.Authorize == nil || !seen.Authorize(nil) {
+		t.Fatal("expected the attached ProxyContext to carry server.Authorize")
+	}
+}
+
+type fakeStatsClient struct {
+	gauges map[string]int64
+}
+
+func (f *fakeStatsClient) Gauge(stat string, value int64) {
+	if f.gauges == nil {
+		f.gauges = map[string]int64{}
+	}
+	f.gauges[stat] = value
+}
+
+func TestMiddlewareReportsCacheMetrics(t *testing.T) {
+	stats := &fakeStatsClient{}
+	server := &ProxyServer{Cache: &fakeMetadataCache{}, Stats: stats}
+	handler := server.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		GetProxyContext(r).Cache.GetAccount("a")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/a", nil))
+	if stats.gauges["account_cache.misses"] != 1 {
+		t.Fatalf("expected one reported miss, got %+v", stats.gauges)
+	}
+}
@@ -0,0 +1,245 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package proxyserver
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/openstack/swift/go/hummingbird"
+	"github.com/openstack/swift/go/hummingbird/cache"
+	"github.com/openstack/swift/go/proxyserver/proto"
+)
+
+// listingChunkSize is the number of entries streamed per GetAccountResponse
+// message, so a large listing doesn't arrive as one oversized gRPC frame.
+const listingChunkSize = 1000
+
+// AccountGRPCServer implements proto.AccountServiceServer on top of the
+// same ProxyServer used by the HTTP account handlers, so both surfaces
+// share backend client and authorization behavior.
+type AccountGRPCServer struct {
+	server     *ProxyServer
+	newContext func(*http.Request) *ProxyContext
+}
+
+// NewAccountGRPCServer returns a gRPC server for account operations backed
+// by server. newContext builds the per-request ProxyContext the same way
+// the HTTP middleware does, so Authorize (and the account cache) stay in
+// lockstep between the HTTP and gRPC surfaces instead of consulting a
+// separate, server-wide hook.
+func NewAccountGRPCServer(server *ProxyServer, newContext func(*http.Request) *ProxyContext) *AccountGRPCServer {
+	return &AccountGRPCServer{server: server, newContext: newContext}
+}
+
+// Serve registers the AccountService on s.
+func (g *AccountGRPCServer) Serve(s *grpc.Server) {
+	proto.RegisterAccountServiceServer(s, g)
+}
+
+// RunAccountGRPCServer listens on addr and serves the AccountService backed
+// by server, blocking until the listener fails. This is the gRPC surface's
+// equivalent of the HTTP handlers' ListenAndServe entry point, sharing the
+// same ProxyServer and so the same backend, Authorize hook, and account
+// cache.
+func RunAccountGRPCServer(addr string, server *ProxyServer) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s := grpc.NewServer()
+	NewAccountGRPCServer(server, server.NewContext).Serve(s)
+	return s.Serve(listener)
+}
+
+// headersFromMetadata maps incoming gRPC metadata to the Swift
+// "X-Account-*" style http.Header the backend client and Authorize hook
+// expect.
+func headersFromMetadata(ctx context.Context) http.Header {
+	headers := http.Header{}
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return headers
+	}
+	for k, values := range md {
+		for _, v := range values {
+			headers.Add(k, v)
+		}
+	}
+	return headers
+}
+
+// metadataFromHeaders is the inverse of headersFromMetadata, used to
+// return backend response headers to gRPC clients.
+func metadataFromHeaders(headers http.Header) *proto.Metadata {
+	m := make(map[string]string, len(headers))
+	for k := range headers {
+		m[k] = headers.Get(k)
+	}
+	return &proto.Metadata{Headers: m}
+}
+
+// requestContext builds the synthetic request the gRPC call's metadata maps
+// onto and the ProxyContext g.newContext derives from it, so authorize and
+// the account cache below consult the exact same per-request state the HTTP
+// account handlers do.
+func (g *AccountGRPCServer) requestContext(ctx context.Context, account string) (*http.Request, *ProxyContext) {
+	request := &http.Request{
+		Header: headersFromMetadata(ctx),
+		URL:    &url.URL{Path: "/v1/" + account},
+	}
+	return request, g.newContext(request)
+}
+
+// authorize runs the gRPC call through the same per-request
+// ProxyContext.Authorize hook the HTTP account handlers use, rather than a
+// server-wide hook, so per-request authorization policy applies to the
+// gRPC surface too.
+func (g *AccountGRPCServer) authorize(ctx context.Context, account string) bool {
+	request, proxyCtx := g.requestContext(ctx, account)
+	if proxyCtx == nil || proxyCtx.Authorize == nil {
+		return true
+	}
+	return proxyCtx.Authorize(request)
+}
+
+// decodeListing turns the backend's "format=json" account listing body
+// into the entries streamed over gRPC. A delimiter listing mixes in
+// {"subdir": "..."} pseudo-entries alongside the usual
+// name/count/bytes/last_modified ones, so both shapes are decoded.
+func decodeListing(body interface {
+	Read(p []byte) (int, error)
+}) ([]*proto.ListingEntry, error) {
+	var raw []struct {
+		Name         string `json:"name"`
+		Count        int64  `json:"count"`
+		Bytes        int64  `json:"bytes"`
+		LastModified string `json:"last_modified"`
+		Subdir       string `json:"subdir"`
+	}
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		return nil, err
+	}
+	entries := make([]*proto.ListingEntry, len(raw))
+	for i, r := range raw {
+		entries[i] = &proto.ListingEntry{
+			Name:         r.Name,
+			Count:        r.Count,
+			Bytes:        r.Bytes,
+			LastModified: r.LastModified,
+			Subdir:       r.Subdir,
+		}
+	}
+	return entries, nil
+}
+
+func (g *AccountGRPCServer) GetAccount(req *proto.GetAccountRequest, stream proto.AccountService_GetAccountServer) error {
+	if !g.authorize(stream.Context(), req.Account) {
+		return stream.Send(&proto.GetAccountResponse{StatusCode: http.StatusUnauthorized})
+	}
+	options := map[string]string{
+		"marker":     req.Marker,
+		"end_marker": req.EndMarker,
+		"prefix":     req.Prefix,
+		"delimiter":  req.Delimiter,
+		"format":     "json",
+	}
+	if req.Limit > 0 {
+		options["limit"] = strconv.FormatInt(req.Limit, 10)
+	}
+	r, headers, code := g.server.C.GetAccount(req.Account, options, headersFromMetadata(stream.Context()))
+	if r == nil {
+		return stream.Send(&proto.GetAccountResponse{StatusCode: int32(code), ResponseHeaders: metadataFromHeaders(headers)})
+	}
+	defer r.Close()
+	entries, err := decodeListing(r)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(entries); i += listingChunkSize {
+		end := i + listingChunkSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		resp := &proto.GetAccountResponse{Entries: entries[i:end]}
+		if i == 0 {
+			resp.StatusCode = int32(code)
+			resp.ResponseHeaders = metadataFromHeaders(headers)
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	if len(entries) == 0 {
+		return stream.Send(&proto.GetAccountResponse{StatusCode: int32(code), ResponseHeaders: metadataFromHeaders(headers)})
+	}
+	return nil
+}
+
+func (g *AccountGRPCServer) HeadAccount(ctx context.Context, req *proto.HeadAccountRequest) (*proto.HeadAccountResponse, error) {
+	if !g.authorize(ctx, req.Account) {
+		return &proto.HeadAccountResponse{StatusCode: http.StatusUnauthorized}, nil
+	}
+	_, proxyCtx := g.requestContext(ctx, req.Account)
+	if proxyCtx != nil && proxyCtx.Cache != nil {
+		if info, ok := proxyCtx.Cache.GetAccount(req.Account); ok {
+			return &proto.HeadAccountResponse{StatusCode: int32(info.Code), ResponseHeaders: metadataFromHeaders(info.Headers)}, nil
+		}
+	}
+	headers, code := g.server.C.HeadAccount(req.Account, headersFromMetadata(ctx))
+	if proxyCtx != nil && proxyCtx.Cache != nil {
+		ttl := time.Duration(0)
+		if code < 200 || code >= 300 {
+			ttl = errorCacheTTL
+		}
+		proxyCtx.Cache.SetAccount(req.Account, &cache.AccountInfo{Headers: headers, Code: code}, ttl)
+	}
+	return &proto.HeadAccountResponse{StatusCode: int32(code), ResponseHeaders: metadataFromHeaders(headers)}, nil
+}
+
+func (g *AccountGRPCServer) PutAccount(ctx context.Context, req *proto.PutAccountRequest) (*proto.StatusResponse, error) {
+	if !g.authorize(ctx, req.Account) {
+		return &proto.StatusResponse{StatusCode: http.StatusUnauthorized}, nil
+	}
+	headers := headersFromMetadata(ctx)
+	headers.Set("X-Timestamp", hummingbird.GetTimestamp())
+	code := g.server.C.PutAccount(req.Account, headers)
+	if _, proxyCtx := g.requestContext(ctx, req.Account); proxyCtx != nil && proxyCtx.Cache != nil {
+		proxyCtx.Cache.InvalidateAccount(req.Account)
+	}
+	return &proto.StatusResponse{StatusCode: int32(code)}, nil
+}
+
+func (g *AccountGRPCServer) DeleteAccount(ctx context.Context, req *proto.DeleteAccountRequest) (*proto.StatusResponse, error) {
+	if !g.authorize(ctx, req.Account) {
+		return &proto.StatusResponse{StatusCode: http.StatusUnauthorized}, nil
+	}
+	headers := headersFromMetadata(ctx)
+	headers.Set("X-Timestamp", hummingbird.GetTimestamp())
+	code := g.server.C.DeleteAccount(req.Account, headers)
+	if _, proxyCtx := g.requestContext(ctx, req.Account); proxyCtx != nil && proxyCtx.Cache != nil {
+		proxyCtx.Cache.InvalidateAccount(req.Account)
+	}
+	return &proto.StatusResponse{StatusCode: int32(code)}, nil
+}
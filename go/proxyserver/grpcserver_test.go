@@ -0,0 +1,180 @@
+//  Copyright (c) 2015 Rackspace
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+//  implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package proxyserver
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/openstack/swift/go/hummingbird/cache"
+	"github.com/openstack/swift/go/proxyserver/proto"
+)
+
+// fakeBackend is a minimal ProxyBackend stub; only the account methods the
+// gRPC account handlers exercise return configured values, the rest are
+// unused by these tests.
+type fakeBackend struct {
+	headHeaders http.Header
+	headCode    int
+	putCode     int
+	deleteCode  int
+}
+
+func (f *fakeBackend) GetAccount(account string, options map[string]string, headers http.Header) (io.ReadCloser, http.Header, int) {
+	return nil, nil, 0
+}
+func (f *fakeBackend) HeadAccount(account string, headers http.Header) (http.Header, int) {
+	return f.headHeaders, f.headCode
+}
+func (f *fakeBackend) PutAccount(account string, headers http.Header) int    { return f.putCode }
+func (f *fakeBackend) DeleteAccount(account string, headers http.Header) int { return f.deleteCode }
+
+func (f *fakeBackend) GetContainer(account, container string, options map[string]string, headers http.Header) (io.ReadCloser, http.Header, int) {
+	return nil, nil, 0
+}
+func (f *fakeBackend) HeadContainer(account, container string, headers http.Header) (http.Header, int) {
+	return nil, 0
+}
+func (f *fakeBackend) PutContainer(account, container string, headers http.Header) int    { return 0 }
+func (f *fakeBackend) DeleteContainer(account, container string, headers http.Header) int { return 0 }
+
+func (f *fakeBackend) GetObject(account, container, object string, headers http.Header) (io.ReadCloser, http.Header, int) {
+	return nil, nil, 0
+}
+func (f *fakeBackend) HeadObject(account, container, object string, headers http.Header) (http.Header, int) {
+	return nil, 0
+}
+func (f *fakeBackend) PutObject(account, container, object string, headers http.Header, body io.Reader) int {
+	return 0
+}
+func (f *fakeBackend) DeleteObject(account, container, object string, headers http.Header) int {
+	return 0
+}
+
+// spyCache is a cache.MetadataCache that records what the gRPC handlers did
+// with it, so tests can assert on consult/invalidate behavior without a
+// real Redis connection.
+type spyCache struct {
+	hitInfo     *cache.AccountInfo
+	setInfo     *cache.AccountInfo
+	setTTL      time.Duration
+	invalidated []string
+}
+
+func (s *spyCache) GetAccount(account string) (*cache.AccountInfo, bool) {
+	if s.hitInfo == nil {
+		return nil, false
+	}
+	return s.hitInfo, true
+}
+func (s *spyCache) SetAccount(account string, info *cache.AccountInfo, ttl time.Duration) {
+	s.setInfo, s.setTTL = info, ttl
+}
+func (s *spyCache) InvalidateAccount(account string) { s.invalidated = append(s.invalidated, account) }
+func (s *spyCache) Hits() int64                      { return 0 }
+func (s *spyCache) Misses() int64                    { return 0 }
+
+func TestDecodeListingRegularEntries(t *testing.T) {
+	body := strings.NewReader(`[{"name":"obj1","count":0,"bytes":12,"last_modified":"2016-01-01T00:00:00"}]`)
+	entries, err := decodeListing(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "obj1" || entries[0].Bytes != 12 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestDecodeListingSubdirEntries(t *testing.T) {
+	body := strings.NewReader(`[{"subdir":"photos/"},{"name":"obj1","bytes":5}]`)
+	entries, err := decodeListing(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Subdir != "photos/" || entries[0].Name != "" {
+		t.Fatalf("expected a subdir pseudo-entry, got %+v", entries[0])
+	}
+	if entries[1].Name != "obj1" {
+		t.Fatalf("expected the regular entry to follow it, got %+v", entries[1])
+	}
+}
+
+func TestHeadAccountConsultsCacheBeforeBackend(t *testing.T) {
+	spy := &spyCache{hitInfo: &cache.AccountInfo{Headers: http.Header{"X-Test": []string{"1"}}, Code: 204}}
+	server := &ProxyServer{C: &fakeBackend{headCode: 500}, Cache: spy}
+	g := NewAccountGRPCServer(server, server.NewContext)
+	resp, err := g.HeadAccount(context.Background(), &proto.HeadAccountRequest{Account: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 204 {
+		t.Fatalf("expected the cached status code, got %d", resp.StatusCode)
+	}
+}
+
+func TestHeadAccountFillsCacheOnMiss(t *testing.T) {
+	spy := &spyCache{}
+	server := &ProxyServer{C: &fakeBackend{headHeaders: http.Header{"X-Test": []string{"1"}}, headCode: 204}, Cache: spy}
+	g := NewAccountGRPCServer(server, server.NewContext)
+	resp, err := g.HeadAccount(context.Background(), &proto.HeadAccountRequest{Account: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 204 {
+		t.Fatalf("unexpected status code: %d", resp.StatusCode)
+	}
+	if spy.setInfo == nil || spy.setInfo.Code != 204 || spy.setTTL != 0 {
+		t.Fatalf("expected a success response to be cached with ttl 0 (the cache's default AccountTTL), got %+v ttl=%v", spy.setInfo, spy.setTTL)
+	}
+}
+
+func TestPutAccountInvalidatesCache(t *testing.T) {
+	spy := &spyCache{}
+	server := &ProxyServer{C: &fakeBackend{putCode: 201}, Cache: spy}
+	g := NewAccountGRPCServer(server, server.NewContext)
+	if _, err := g.PutAccount(context.Background(), &proto.PutAccountRequest{Account: "a"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spy.invalidated) != 1 || spy.invalidated[0] != "a" {
+		t.Fatalf("expected PutAccount to invalidate the cache, got %+v", spy.invalidated)
+	}
+}
+
+func TestDeleteAccountInvalidatesCache(t *testing.T) {
+	spy := &spyCache{}
+	server := &ProxyServer{C: &fakeBackend{deleteCode: 204}, Cache: spy}
+	g := NewAccountGRPCServer(server, server.NewContext)
+	if _, err := g.DeleteAccount(context.Background(), &proto.DeleteAccountRequest{Account: "a"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spy.invalidated) != 1 || spy.invalidated[0] != "a" {
+		t.Fatalf("expected DeleteAccount to invalidate the cache, got %+v", spy.invalidated)
+	}
+}
+
+func TestRunAccountGRPCServerReturnsListenError(t *testing.T) {
+	if err := RunAccountGRPCServer("not-a-valid-address", &ProxyServer{}); err == nil {
+		t.Fatal("expected a listen error for an invalid address")
+	}
+}